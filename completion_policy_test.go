@@ -0,0 +1,96 @@
+package main
+
+import "testing"
+
+func TestDefaultCompletionPolicy(t *testing.T) {
+	session := Session{Username: "user@example.com", Password: "hunter2"}
+	tokens := []Token{{Name: "session_id", Value: "abc"}}
+
+	if !defaultCompletionPolicy.IsSatisfied(session, tokens) {
+		t.Fatal("expected default policy to be satisfied by credentials + one token")
+	}
+
+	noTokens := []Token{}
+	if defaultCompletionPolicy.IsSatisfied(session, noTokens) {
+		t.Fatal("expected default policy to be unsatisfied with no tokens")
+	}
+
+	noCreds := Session{}
+	if defaultCompletionPolicy.IsSatisfied(noCreds, tokens) {
+		t.Fatal("expected default policy to be unsatisfied without credentials")
+	}
+}
+
+func TestBuiltinMicrosoftPolicy(t *testing.T) {
+	policies := builtinCompletionPolicies()
+	policy, ok := policies["login.microsoftonline.com"]
+	if !ok {
+		t.Fatal("expected a built-in login.microsoftonline.com policy")
+	}
+
+	session := Session{Username: "user@contoso.com", Password: "hunter2"}
+
+	incomplete := []Token{{Name: "ESTSAUTH", Value: "x"}}
+	if policy.IsSatisfied(session, incomplete) {
+		t.Fatal("expected policy to require both ESTSAUTH and ESTSAUTHPERSISTENT")
+	}
+
+	complete := []Token{
+		{Name: "ESTSAUTH", Value: "x"},
+		{Name: "ESTSAUTHPERSISTENT", Value: "y"},
+	}
+	if !policy.IsSatisfied(session, complete) {
+		t.Fatal("expected policy to be satisfied once both required tokens are present")
+	}
+}
+
+func TestBuiltinGooglePolicy(t *testing.T) {
+	policies := builtinCompletionPolicies()
+	policy, ok := policies["accounts.google.com"]
+	if !ok {
+		t.Fatal("expected a built-in accounts.google.com policy")
+	}
+
+	session := Session{Username: "user@gmail.com", Password: "hunter2"}
+	tokens := []Token{{Name: "SID", Value: "x"}, {Name: "HSID", Value: "y"}}
+
+	if !policy.IsSatisfied(session, tokens) {
+		t.Fatal("expected policy to be satisfied with SID and HSID present")
+	}
+}
+
+func TestCompletionPolicySetPolicyFor(t *testing.T) {
+	set := CompletionPolicySet{
+		Default: defaultCompletionPolicy,
+		ByDomain: map[string]CompletionPolicy{
+			"login.microsoftonline.com": {Domain: "login.microsoftonline.com", RequiredTokens: []string{"ESTSAUTH"}},
+		},
+	}
+
+	exact := set.PolicyFor("login.microsoftonline.com")
+	if len(exact.RequiredTokens) != 1 || exact.RequiredTokens[0] != "ESTSAUTH" {
+		t.Fatalf("expected exact domain match, got %+v", exact)
+	}
+
+	subdomain := set.PolicyFor("sso.login.microsoftonline.com")
+	if len(subdomain.RequiredTokens) != 1 || subdomain.RequiredTokens[0] != "ESTSAUTH" {
+		t.Fatalf("expected subdomain to inherit parent policy, got %+v", subdomain)
+	}
+
+	unrelated := set.PolicyFor("example.com")
+	if len(unrelated.RequiredTokens) != 0 {
+		t.Fatalf("expected unrelated domain to fall back to Default, got %+v", unrelated)
+	}
+}
+
+func TestExcludeHTTPOnly(t *testing.T) {
+	tokens := []Token{
+		{Name: "visible", HttpOnly: false},
+		{Name: "hidden", HttpOnly: true},
+	}
+
+	kept := excludeHTTPOnly(tokens)
+	if len(kept) != 1 || kept[0].Name != "visible" {
+		t.Fatalf("expected only the non-HttpOnly token to survive, got %+v", kept)
+	}
+}
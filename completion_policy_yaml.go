@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseCompletionPolicyYAML parses the small DSL used to declare custom
+// per-domain completion policies in config, e.g.:
+//
+//   - domain: login.example.com
+//     required_tokens: [SESSIONID, XSRF-TOKEN]
+//     allow_http_only: true
+//     min_token_count: 2
+//     require_credentials: true
+//
+// A parse error is logged and treated as no custom policies, so a typo in
+// config degrades to the built-in profiles rather than stopping delivery.
+func parseCompletionPolicyYAML(raw string) []CompletionPolicy {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	var policies []CompletionPolicy
+	if err := yaml.Unmarshal([]byte(raw), &policies); err != nil {
+		fmt.Println("completion policy: parsing config YAML:", err)
+		return nil
+	}
+	return policies
+}
+
+// normalizeDomain lowercases a domain for use as a CompletionPolicySet key.
+func normalizeDomain(domain string) string {
+	return strings.ToLower(domain)
+}
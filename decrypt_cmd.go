@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// DecryptPrivateKeyEnv is the environment variable the `evilginx-monitor
+// decrypt` subcommand reads the recipient's X25519 private key from.
+const DecryptPrivateKeyEnv = "EVILGINX_MONITOR_DECRYPT_KEY"
+
+// RunDecryptCommand reads the recipient private key from
+// DecryptPrivateKeyEnv, decrypts the `.enc` envelope at path, and prints
+// the original JS/JSON payload to stdout. It backs the `evilginx-monitor
+// decrypt` CLI subcommand.
+func RunDecryptCommand(path string) error {
+	privateKeyB64 := os.Getenv(DecryptPrivateKeyEnv)
+	if privateKeyB64 == "" {
+		return fmt.Errorf("decrypt: %s is not set", DecryptPrivateKeyEnv)
+	}
+
+	plaintext, err := decryptAttachment(path, privateKeyB64)
+	if err != nil {
+		return fmt.Errorf("decrypt: %v", err)
+	}
+
+	fmt.Println(string(plaintext))
+	return nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// recipientStanza wraps a single file key to one recipient's X25519 public
+// key via a fresh ephemeral keypair — the NaCl-box equivalent of
+// libsodium's crypto_box_seal. No two recipients, or the sender, ever share
+// a long-term secret.
+type recipientStanza struct {
+	EphemeralPublicKey string `json:"ephemeralPublicKey"`
+	Nonce              string `json:"nonce"`
+	WrappedKey         string `json:"wrappedKey"`
+}
+
+// encryptedEnvelope is the on-disk `.enc` container: the payload sealed
+// under a random file key with NaCl secretbox, and that key wrapped once
+// per recipient so multiple operators can each decrypt the same drop.
+type encryptedEnvelope struct {
+	Version    int               `json:"version"`
+	Nonce      string            `json:"nonce"`
+	Ciphertext string            `json:"ciphertext"`
+	Recipients []recipientStanza `json:"recipients"`
+}
+
+// encryptAttachment reads the plaintext file at path, seals it under a
+// fresh random key with NaCl secretbox, wraps that key to each of
+// recipientPublicKeys (base64-encoded X25519 public keys), and writes the
+// result to path+".enc". The plaintext is removed once the encrypted file
+// is safely on disk. It returns the encrypted file's path and a short
+// fingerprint (the first 4 bytes of the plaintext's SHA-256, hex-encoded)
+// suitable for the notification caption.
+func encryptAttachment(path string, recipientPublicKeys []string) (encPath string, fingerprint string, err error) {
+	if len(recipientPublicKeys) == 0 {
+		return "", "", fmt.Errorf("encryptAttachment: no recipients configured")
+	}
+
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("encryptAttachment: reading %s: %v", path, err)
+	}
+
+	var fileKey [32]byte
+	if _, err := rand.Read(fileKey[:]); err != nil {
+		return "", "", fmt.Errorf("encryptAttachment: generating file key: %v", err)
+	}
+
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", "", fmt.Errorf("encryptAttachment: generating nonce: %v", err)
+	}
+	ciphertext := secretbox.Seal(nil, plaintext, &nonce, &fileKey)
+
+	envelope := encryptedEnvelope{
+		Version:    1,
+		Nonce:      base64.StdEncoding.EncodeToString(nonce[:]),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}
+
+	for _, recipientB64 := range recipientPublicKeys {
+		stanza, err := wrapFileKey(fileKey, recipientB64)
+		if err != nil {
+			return "", "", fmt.Errorf("encryptAttachment: wrapping key for recipient: %v", err)
+		}
+		envelope.Recipients = append(envelope.Recipients, stanza)
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return "", "", fmt.Errorf("encryptAttachment: marshalling envelope: %v", err)
+	}
+
+	encPath = path + ".enc"
+	if err := os.WriteFile(encPath, data, 0o600); err != nil {
+		return "", "", fmt.Errorf("encryptAttachment: writing %s: %v", encPath, err)
+	}
+	if err := os.Remove(path); err != nil {
+		fmt.Printf("encryptAttachment: warning: failed to remove plaintext %s: %v\n", path, err)
+	}
+
+	sum := sha256.Sum256(plaintext)
+	fingerprint = fmt.Sprintf("%x", sum[:4])
+	return encPath, fingerprint, nil
+}
+
+// encryptAttachments encrypts each path in attachmentPaths for
+// recipientPublicKeys and appends one line to message listing every
+// attachment's fingerprint, so the caption lets recipients confirm
+// they're decrypting the right drop out of band. If any attachment fails
+// to encrypt, it returns that error and no paths, so nothing is sent in
+// the clear; .enc files already written for earlier attachments are left
+// on disk.
+func encryptAttachments(attachmentPaths []string, message string, recipientPublicKeys []string) ([]string, string, error) {
+	encryptedPaths := make([]string, 0, len(attachmentPaths))
+	var fingerprints []string
+
+	for _, path := range attachmentPaths {
+		encPath, fingerprint, err := encryptAttachment(path, recipientPublicKeys)
+		if err != nil {
+			return nil, "", err
+		}
+		encryptedPaths = append(encryptedPaths, encPath)
+		fingerprints = append(fingerprints, fingerprint)
+	}
+
+	if len(fingerprints) > 0 {
+		message = fmt.Sprintf("%s\n🔒 Fingerprint: %s", message, strings.Join(fingerprints, ", "))
+	}
+	return encryptedPaths, message, nil
+}
+
+// wrapFileKey seals fileKey to recipientB64 (a base64-encoded X25519
+// public key) using a fresh ephemeral keypair.
+func wrapFileKey(fileKey [32]byte, recipientB64 string) (recipientStanza, error) {
+	recipientPubBytes, err := base64.StdEncoding.DecodeString(recipientB64)
+	if err != nil || len(recipientPubBytes) != 32 {
+		return recipientStanza{}, fmt.Errorf("invalid recipient public key %q", recipientB64)
+	}
+	var recipientPub [32]byte
+	copy(recipientPub[:], recipientPubBytes)
+
+	ephemeralPub, ephemeralPriv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return recipientStanza{}, fmt.Errorf("generating ephemeral keypair: %v", err)
+	}
+
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return recipientStanza{}, fmt.Errorf("generating nonce: %v", err)
+	}
+
+	wrapped := box.Seal(nil, fileKey[:], &nonce, &recipientPub, ephemeralPriv)
+
+	return recipientStanza{
+		EphemeralPublicKey: base64.StdEncoding.EncodeToString(ephemeralPub[:]),
+		Nonce:              base64.StdEncoding.EncodeToString(nonce[:]),
+		WrappedKey:         base64.StdEncoding.EncodeToString(wrapped),
+	}, nil
+}
+
+// decryptAttachment reverses encryptAttachment using the holder's X25519
+// private key (base64-encoded), trying each recipient stanza in turn until
+// one unwraps — a given private key will normally match exactly one
+// stanza in the envelope.
+func decryptAttachment(encPath string, privateKeyB64 string) ([]byte, error) {
+	data, err := os.ReadFile(encPath)
+	if err != nil {
+		return nil, fmt.Errorf("decryptAttachment: reading %s: %v", encPath, err)
+	}
+
+	var envelope encryptedEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("decryptAttachment: parsing envelope: %v", err)
+	}
+
+	privBytes, err := base64.StdEncoding.DecodeString(privateKeyB64)
+	if err != nil || len(privBytes) != 32 {
+		return nil, fmt.Errorf("decryptAttachment: invalid private key")
+	}
+	var priv [32]byte
+	copy(priv[:], privBytes)
+
+	var fileKey [32]byte
+	var unwrapped bool
+	for _, stanza := range envelope.Recipients {
+		key, ok, err := tryUnwrapFileKey(stanza, priv)
+		if err != nil || !ok {
+			continue
+		}
+		fileKey = key
+		unwrapped = true
+		break
+	}
+	if !unwrapped {
+		return nil, fmt.Errorf("decryptAttachment: no recipient stanza matched this private key")
+	}
+
+	nonceBytes, err := base64.StdEncoding.DecodeString(envelope.Nonce)
+	if err != nil || len(nonceBytes) != 24 {
+		return nil, fmt.Errorf("decryptAttachment: invalid nonce")
+	}
+	var nonce [24]byte
+	copy(nonce[:], nonceBytes)
+
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decryptAttachment: invalid ciphertext")
+	}
+
+	plaintext, ok := secretbox.Open(nil, ciphertext, &nonce, &fileKey)
+	if !ok {
+		return nil, fmt.Errorf("decryptAttachment: secretbox authentication failed")
+	}
+	return plaintext, nil
+}
+
+// tryUnwrapFileKey attempts to open a single recipient stanza with priv,
+// returning ok=false (not an error) when priv simply isn't the matching
+// key for this stanza.
+func tryUnwrapFileKey(stanza recipientStanza, priv [32]byte) (key [32]byte, ok bool, err error) {
+	ephemeralPubBytes, err := base64.StdEncoding.DecodeString(stanza.EphemeralPublicKey)
+	if err != nil || len(ephemeralPubBytes) != 32 {
+		return key, false, fmt.Errorf("invalid ephemeral public key")
+	}
+	var ephemeralPub [32]byte
+	copy(ephemeralPub[:], ephemeralPubBytes)
+
+	nonceBytes, err := base64.StdEncoding.DecodeString(stanza.Nonce)
+	if err != nil || len(nonceBytes) != 24 {
+		return key, false, fmt.Errorf("invalid nonce")
+	}
+	var nonce [24]byte
+	copy(nonce[:], nonceBytes)
+
+	wrapped, err := base64.StdEncoding.DecodeString(stanza.WrappedKey)
+	if err != nil {
+		return key, false, fmt.Errorf("invalid wrapped key")
+	}
+
+	opened, ok := box.Open(nil, wrapped, &nonce, &ephemeralPub, &priv)
+	if !ok {
+		return key, false, nil
+	}
+	copy(key[:], opened)
+	return key, true, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExportFormat identifies a supported attachment format for a captured
+// session's cookies.
+type ExportFormat string
+
+const (
+	FormatJSSnippet       ExportFormat = "js"             // document.cookie replay snippet (the original format)
+	FormatNetscapeCookies ExportFormat = "netscape"       // curl/wget-style cookies.txt
+	FormatEditThisCookie  ExportFormat = "editthiscookie" // EditThisCookie browser extension import
+	FormatPuppeteer       ExportFormat = "puppeteer"      // page.setCookie / context.addCookies
+	FormatHAR             ExportFormat = "har"            // HAR archive with a single cookie-bearing entry
+)
+
+// Exporter renders a session's tokens into one attachment format, writing
+// the result to a temp file and returning its path.
+type Exporter interface {
+	Format() ExportFormat
+	Export(session Session, tokens []Token) (string, error)
+}
+
+// exportersForFormats resolves the configured format names into Exporters,
+// skipping any name it doesn't recognize.
+func exportersForFormats(formats []string) []Exporter {
+	var exporters []Exporter
+	for _, name := range formats {
+		switch ExportFormat(name) {
+		case FormatJSSnippet:
+			exporters = append(exporters, JSSnippetExporter{})
+		case FormatNetscapeCookies:
+			exporters = append(exporters, NetscapeExporter{})
+		case FormatEditThisCookie:
+			exporters = append(exporters, EditThisCookieExporter{})
+		case FormatPuppeteer:
+			exporters = append(exporters, PuppeteerExporter{})
+		case FormatHAR:
+			exporters = append(exporters, HARExporter{})
+		default:
+			fmt.Printf("exporter: unknown format %q, skipping\n", name)
+		}
+	}
+	return exporters
+}
+
+// exportAttachments runs every configured exporter over session and returns
+// the resulting attachment paths. Callers are responsible for removing them.
+func exportAttachments(session Session, exporters []Exporter) ([]string, error) {
+	tokens, err := tokensForExport(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, exporter := range exporters {
+		path, err := exporter.Export(session, tokens)
+		if err != nil {
+			fmt.Printf("exporter: %s failed: %v\n", exporter.Format(), err)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// extractTokensWithExpiry behaves like extractTokens but preserves
+// ExpirationDate, which the JS-snippet flow deliberately strips since
+// document.cookie can't carry it. Non-JS export formats need the real
+// value.
+func extractTokensWithExpiry(input map[string]map[string]map[string]interface{}) []Token {
+	var tokens []Token
+
+	for domain, tokenGroup := range input {
+		for _, tokenData := range tokenGroup {
+			t := tokenFromRaw(domain, tokenData)
+
+			if exp, ok := tokenData["ExpirationDate"].(float64); ok {
+				expInt := int64(exp)
+				t.ExpirationDate = &expInt
+			} else if exp, ok := tokenData["expirationDate"].(float64); ok {
+				expInt := int64(exp)
+				t.ExpirationDate = &expInt
+			}
+
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// tokensForExport consolidates every token group on session with
+// expiration metadata intact, for use by exporters other than the plain JS
+// snippet.
+func tokensForExport(session Session) ([]Token, error) {
+	groups := map[string]interface{}{
+		"tokens":     session.Tokens,
+		"httpTokens": session.HTTPTokens,
+		"bodyTokens": session.BodyTokens,
+		"custom":     session.Custom,
+	}
+
+	var consolidated []Token
+	for name, group := range groups {
+		groupJSON, err := json.Marshal(group)
+		if err != nil {
+			return nil, fmt.Errorf("exporter: marshalling %s: %v", name, err)
+		}
+		if string(groupJSON) == "null" {
+			continue
+		}
+
+		var rawTokens map[string]map[string]map[string]interface{}
+		if err := json.Unmarshal(groupJSON, &rawTokens); err != nil {
+			return nil, fmt.Errorf("exporter: parsing %s: %v", name, err)
+		}
+
+		consolidated = append(consolidated, extractTokensWithExpiry(rawTokens)...)
+	}
+
+	return consolidated, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// EditThisCookieExporter renders tokens as a JSON array compatible with the
+// EditThisCookie browser extension's import feature. Token's json tags
+// already match the extension's field names (expirationDate, hostOnly,
+// sameSite, storeId), so this is a direct marshal.
+type EditThisCookieExporter struct{}
+
+func (EditThisCookieExporter) Format() ExportFormat {
+	return FormatEditThisCookie
+}
+
+func (EditThisCookieExporter) Export(session Session, tokens []Token) (string, error) {
+	path, err := TempFileName(session, "editthiscookie.json")
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("editthiscookie exporter: marshalling tokens: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("editthiscookie exporter: writing %s: %v", path, err)
+	}
+	return path, nil
+}
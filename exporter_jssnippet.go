@@ -0,0 +1,15 @@
+package main
+
+// JSSnippetExporter renders the original document.cookie replay snippet.
+// It ignores the consolidated tokens passed in and delegates to
+// createTxtFile, which does its own parsing to keep the JS snippet's
+// existing cookie-stripping behavior untouched.
+type JSSnippetExporter struct{}
+
+func (JSSnippetExporter) Format() ExportFormat {
+	return FormatJSSnippet
+}
+
+func (JSSnippetExporter) Export(session Session, tokens []Token) (string, error) {
+	return createTxtFile(session)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NetscapeExporter renders tokens as a Netscape-format cookies.txt, the
+// tab-separated layout understood by curl/wget and most cookie-import
+// tooling.
+type NetscapeExporter struct{}
+
+func (NetscapeExporter) Format() ExportFormat {
+	return FormatNetscapeCookies
+}
+
+func (NetscapeExporter) Export(session Session, tokens []Token) (string, error) {
+	path, err := TempFileName(session, "cookies.txt")
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("# Netscape HTTP Cookie File\n")
+
+	for _, t := range tokens {
+		domain := t.Domain
+		if t.HttpOnly {
+			domain = "#HttpOnly_" + domain
+		}
+
+		includeSubdomains := "FALSE"
+		if !t.HostOnly {
+			includeSubdomains = "TRUE"
+		}
+
+		secure := "FALSE"
+		if t.Secure {
+			secure = "TRUE"
+		}
+
+		var expiry int64
+		if t.ExpirationDate != nil {
+			expiry = *t.ExpirationDate
+		}
+
+		cookiePath := t.Path
+		if cookiePath == "" {
+			cookiePath = "/"
+		}
+
+		fmt.Fprintf(&b, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain, includeSubdomains, cookiePath, secure, expiry, t.Name, t.Value)
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return "", fmt.Errorf("netscape exporter: writing %s: %v", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// playwrightCookie matches the shape expected by Puppeteer's
+// page.setCookie and Playwright's BrowserContext.addCookies.
+type playwrightCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite,omitempty"`
+}
+
+// PuppeteerExporter renders tokens as a JSON array ready for
+// context.addCookies (Playwright) or page.setCookie (Puppeteer).
+type PuppeteerExporter struct{}
+
+func (PuppeteerExporter) Format() ExportFormat {
+	return FormatPuppeteer
+}
+
+func (PuppeteerExporter) Export(session Session, tokens []Token) (string, error) {
+	path, err := TempFileName(session, "puppeteer_cookies.json")
+	if err != nil {
+		return "", err
+	}
+
+	cookies := make([]playwrightCookie, 0, len(tokens))
+	for _, t := range tokens {
+		expires := float64(-1)
+		if t.ExpirationDate != nil {
+			expires = float64(*t.ExpirationDate)
+		}
+
+		cookies = append(cookies, playwrightCookie{
+			Name:     t.Name,
+			Value:    t.Value,
+			Domain:   t.Domain,
+			Path:     t.Path,
+			Expires:  expires,
+			HTTPOnly: t.HttpOnly,
+			Secure:   t.Secure,
+			SameSite: playwrightSameSite(t.SameSite),
+		})
+	}
+
+	data, err := json.MarshalIndent(cookies, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("puppeteer exporter: marshalling cookies: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("puppeteer exporter: writing %s: %v", path, err)
+	}
+	return path, nil
+}
+
+// playwrightSameSite maps the Chrome cookie API's SameSite values onto the
+// "Strict"/"Lax"/"None" enum Playwright and Puppeteer expect.
+func playwrightSameSite(sameSite string) string {
+	switch strings.ToLower(sameSite) {
+	case "strict":
+		return "Strict"
+	case "lax":
+		return "Lax"
+	case "no_restriction", "none":
+		return "None"
+	default:
+		return ""
+	}
+}
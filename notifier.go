@@ -0,0 +1,54 @@
+package main
+
+import "context"
+
+// Notifier delivers session notifications to a single external sink. A
+// Notifier must be safe for concurrent use, since Notify may be invoked from
+// multiple goroutines for different sessions at once.
+type Notifier interface {
+	// Name identifies the sink for config lookups, logging, and the
+	// per-sink rows in the SessionStore (e.g. "telegram", "discord").
+	Name() string
+	// Send posts message as a new notification for session, attaching the
+	// files at attachmentPaths (may be empty), and returns an opaque
+	// handle that can be passed back to Edit to update the same message
+	// later.
+	Send(ctx context.Context, session Session, message string, attachmentPaths []string) (handle string, err error)
+	// Edit updates the message identified by handle with message and
+	// attachmentPaths.
+	Edit(ctx context.Context, handle string, session Session, message string, attachmentPaths []string) error
+}
+
+// buildNotifiers constructs the set of enabled Notifier sinks from config.
+// A sink is only included if its required credentials are present, so users
+// can enable any subset by filling in the corresponding config fields.
+func buildNotifiers(config Config) []Notifier {
+	var notifiers []Notifier
+
+	if config.TelegramToken != "" && config.TelegramChatID != "" {
+		notifiers = append(notifiers, NewTelegramNotifier(config.TelegramChatID, config.TelegramToken))
+	}
+	if config.DiscordWebhookURL != "" {
+		notifiers = append(notifiers, NewDiscordNotifier(config.DiscordWebhookURL))
+	}
+	if config.SlackBotToken != "" && config.SlackChannel != "" {
+		notifiers = append(notifiers, NewSlackNotifier(config.SlackBotToken, config.SlackChannel))
+	}
+	if config.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(config.WebhookURL, config.WebhookSecret))
+	}
+	if config.XMPPJID != "" && config.XMPPPassword != "" && config.XMPPRecipient != "" {
+		notifiers = append(notifiers, NewXMPPNotifier(config.XMPPJID, config.XMPPPassword, config.XMPPServer, config.XMPPRecipient, config.XMPPUploadURL))
+	}
+
+	return notifiers
+}
+
+// splitPrimaryAttachment returns the first attachment path (or "" if none)
+// and the remainder, for sinks whose send/edit calls only carry one file.
+func splitPrimaryAttachment(paths []string) (primary string, extra []string) {
+	if len(paths) == 0 {
+		return "", nil
+	}
+	return paths[0], paths[1:]
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// DiscordNotifier posts session notifications to a Discord channel webhook.
+// Discord webhooks have no concept of a message ID handed back unless
+// `?wait=true` is used, so Edit relies on the webhook's message-edit
+// endpoint with the handle captured from the initial POST.
+type DiscordNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewDiscordNotifier returns a Notifier that posts to the given Discord
+// webhook URL.
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{webhookURL: webhookURL, client: &http.Client{}}
+}
+
+func (d *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+func (d *DiscordNotifier) Send(ctx context.Context, session Session, message string, attachmentPaths []string) (string, error) {
+	resp, err := d.post(ctx, d.webhookURL+"?wait=true", message, attachmentPaths)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var parsed struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("discord: decoding webhook response: %v", err)
+	}
+	return parsed.ID, nil
+}
+
+func (d *DiscordNotifier) Edit(ctx context.Context, handle string, session Session, message string, attachmentPaths []string) error {
+	editURL := fmt.Sprintf("%s/messages/%s", d.webhookURL, handle)
+	resp, err := d.postTo(ctx, editURL, http.MethodPatch, message, attachmentPaths)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord: edit returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// post builds the multipart body (fileN attachments + payload_json) and
+// issues a POST to url.
+func (d *DiscordNotifier) post(ctx context.Context, url, message string, attachmentPaths []string) (*http.Response, error) {
+	return d.postTo(ctx, url, http.MethodPost, message, attachmentPaths)
+}
+
+func (d *DiscordNotifier) postTo(ctx context.Context, url, method, message string, attachmentPaths []string) (*http.Response, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	payload, err := json.Marshal(map[string]string{"content": message})
+	if err != nil {
+		return nil, fmt.Errorf("discord: marshalling payload_json: %v", err)
+	}
+	if err := writer.WriteField("payload_json", string(payload)); err != nil {
+		return nil, fmt.Errorf("discord: writing payload_json field: %v", err)
+	}
+
+	for i, attachmentPath := range attachmentPaths {
+		file, err := os.Open(attachmentPath)
+		if err != nil {
+			return nil, fmt.Errorf("discord: opening attachment: %v", err)
+		}
+
+		part, err := writer.CreateFormFile(fmt.Sprintf("file%d", i), filepath.Base(attachmentPath))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("discord: creating form file: %v", err)
+		}
+		_, copyErr := io.Copy(part, file)
+		file.Close()
+		if copyErr != nil {
+			return nil, fmt.Errorf("discord: copying attachment: %v", copyErr)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("discord: closing multipart writer: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("discord: building request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("discord: request failed: %v", err)
+	}
+	return resp, nil
+}
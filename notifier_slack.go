@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// SlackNotifier posts session notifications to a Slack channel using a bot
+// token: the attachment goes up via files.upload and the caption via
+// chat.postMessage. Edit rewrites the posted message text in place with
+// chat.update; Slack file uploads themselves cannot be edited, so a new
+// drip still uploads a fresh file alongside the updated message.
+type SlackNotifier struct {
+	token   string
+	channel string
+	client  *http.Client
+}
+
+// NewSlackNotifier returns a Notifier that posts to the given Slack channel
+// using the given bot token.
+func NewSlackNotifier(token, channel string) *SlackNotifier {
+	return &SlackNotifier{token: token, channel: channel, client: &http.Client{}}
+}
+
+func (s *SlackNotifier) Name() string {
+	return "slack"
+}
+
+func (s *SlackNotifier) Send(ctx context.Context, session Session, message string, attachmentPaths []string) (string, error) {
+	for _, attachmentPath := range attachmentPaths {
+		if err := s.uploadFile(ctx, attachmentPath, message); err != nil {
+			return "", err
+		}
+	}
+
+	var parsed struct {
+		OK    bool   `json:"ok"`
+		TS    string `json:"ts"`
+		Error string `json:"error"`
+	}
+	if err := s.post(ctx, "chat.postMessage", url.Values{
+		"channel": {s.channel},
+		"text":    {message},
+	}, &parsed); err != nil {
+		return "", err
+	}
+	if !parsed.OK {
+		return "", fmt.Errorf("slack: chat.postMessage failed: %s", parsed.Error)
+	}
+	return parsed.TS, nil
+}
+
+func (s *SlackNotifier) Edit(ctx context.Context, handle string, session Session, message string, attachmentPaths []string) error {
+	for _, attachmentPath := range attachmentPaths {
+		if err := s.uploadFile(ctx, attachmentPath, message); err != nil {
+			return err
+		}
+	}
+
+	var parsed struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := s.post(ctx, "chat.update", url.Values{
+		"channel": {s.channel},
+		"ts":      {handle},
+		"text":    {message},
+	}, &parsed); err != nil {
+		return err
+	}
+	if !parsed.OK {
+		return fmt.Errorf("slack: chat.update failed: %s", parsed.Error)
+	}
+	return nil
+}
+
+func (s *SlackNotifier) uploadFile(ctx context.Context, attachmentPath, caption string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	writer.WriteField("channels", s.channel)
+	writer.WriteField("initial_comment", caption)
+
+	file, err := os.Open(attachmentPath)
+	if err != nil {
+		return fmt.Errorf("slack: opening attachment: %v", err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile("file", filepath.Base(attachmentPath))
+	if err != nil {
+		return fmt.Errorf("slack: creating form file: %v", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("slack: copying attachment: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("slack: closing multipart writer: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/files.upload", body)
+	if err != nil {
+		return fmt.Errorf("slack: building upload request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: upload request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return fmt.Errorf("slack: decoding upload response: %v", err)
+	}
+	if !parsed.OK {
+		return fmt.Errorf("slack: files.upload failed: %s", parsed.Error)
+	}
+	return nil
+}
+
+// post issues a token-authenticated form POST against a Slack Web API
+// method and decodes the JSON response into out.
+func (s *SlackNotifier) post(ctx context.Context, method string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/"+method, bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("slack: building request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("slack: decoding response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// TelegramNotifier sends and edits notifications via the Telegram Bot API,
+// using the existing sendTelegramNotification/editMessageFile helpers.
+// Telegram message edits carry a single attachment, so the first path is
+// treated as the primary attachment; any extra formats go out as
+// uncaptioned follow-up messages.
+type TelegramNotifier struct {
+	chatID string
+	token  string
+}
+
+// NewTelegramNotifier returns a Notifier backed by the given bot token and
+// chat ID.
+func NewTelegramNotifier(chatID, token string) *TelegramNotifier {
+	return &TelegramNotifier{chatID: chatID, token: token}
+}
+
+func (t *TelegramNotifier) Name() string {
+	return "telegram"
+}
+
+func (t *TelegramNotifier) Send(ctx context.Context, session Session, message string, attachmentPaths []string) (string, error) {
+	primary, extra := splitPrimaryAttachment(attachmentPaths)
+
+	messageID, err := sendTelegramNotification(t.chatID, t.token, message, primary)
+	if err != nil {
+		return "", err
+	}
+
+	for _, path := range extra {
+		if _, err := sendTelegramNotification(t.chatID, t.token, "", path); err != nil {
+			fmt.Printf("telegram: sending extra attachment %s: %v\n", path, err)
+		}
+	}
+
+	return strconv.Itoa(messageID), nil
+}
+
+func (t *TelegramNotifier) Edit(ctx context.Context, handle string, session Session, message string, attachmentPaths []string) error {
+	messageID, err := strconv.Atoi(handle)
+	if err != nil {
+		return err
+	}
+
+	primary, extra := splitPrimaryAttachment(attachmentPaths)
+
+	if err := editMessageFile(t.chatID, t.token, messageID, primary, message); err != nil {
+		return err
+	}
+
+	for _, path := range extra {
+		if _, err := sendTelegramNotification(t.chatID, t.token, "", path); err != nil {
+			fmt.Printf("telegram: sending extra attachment %s: %v\n", path, err)
+		}
+	}
+	return nil
+}
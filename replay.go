@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// SessionLookup resolves a persisted session ID back to its full Session
+// data (tokens, credentials, etc.) so a replay can rebuild its attachment.
+// The monitor doesn't own that storage itself; it's supplied by whatever
+// feeds sessions into Notify in the first place.
+type SessionLookup func(sessionID string) (Session, bool)
+
+// ReplaySessions re-sends every session/sink pair in store that never
+// reached a confirmed delivery. It backs the `--replay` CLI flag.
+func ReplaySessions(config Config, store SessionStore, lookup SessionLookup) error {
+	rows, err := store.List()
+	if err != nil {
+		return fmt.Errorf("replay: listing session store: %v", err)
+	}
+
+	notifiers := buildNotifiers(config)
+	notifierByName := make(map[string]Notifier, len(notifiers))
+	for _, n := range notifiers {
+		notifierByName[n.Name()] = n
+	}
+
+	ctx := context.Background()
+	var replayed, skipped int
+
+	for _, row := range rows {
+		if row.Confirmed {
+			continue
+		}
+
+		notifier, ok := notifierByName[row.Sink]
+		if !ok {
+			continue
+		}
+
+		session, ok := lookup(row.SessionID)
+		if !ok {
+			fmt.Printf("replay: session %s no longer available, skipping\n", row.SessionID)
+			skipped++
+			continue
+		}
+
+		tokens, err := tokensForExport(session)
+		if err != nil {
+			fmt.Printf("replay: consolidating tokens for %s: %v\n", row.SessionID, err)
+			skipped++
+			continue
+		}
+		policy := loadCompletionPolicies(config).PolicyFor(domainFromSession(session))
+		message := formatSessionMessage(session, policy.IsSatisfied(session, tokens))
+
+		formats := config.NotifierFormats[row.Sink]
+		if len(formats) == 0 {
+			formats = defaultExportFormats
+		}
+
+		attachmentPaths, err := exportAttachments(session, exportersForFormats(formats))
+		if err != nil {
+			fmt.Printf("replay: creating attachment for %s: %v\n", row.SessionID, err)
+			skipped++
+			continue
+		}
+
+		sinkMessage := message
+		if len(config.EncryptionRecipients) > 0 {
+			plainPaths := attachmentPaths
+			encryptedPaths, encMessage, encErr := encryptAttachments(plainPaths, message, config.EncryptionRecipients)
+			if encErr != nil {
+				fmt.Printf("replay: encrypting attachment for %s: %v\n", row.SessionID, encErr)
+				for _, path := range plainPaths {
+					os.Remove(path)
+				}
+				skipped++
+				continue
+			}
+			attachmentPaths = encryptedPaths
+			sinkMessage = encMessage
+		}
+
+		handle, err := notifier.Send(ctx, session, sinkMessage, attachmentPaths)
+		for _, path := range attachmentPaths {
+			os.Remove(path)
+		}
+		if err != nil {
+			fmt.Printf("replay: resending %s on %s: %v\n", row.SessionID, row.Sink, err)
+			skipped++
+			continue
+		}
+
+		if err := store.MarkProcessed(row.SessionID, row.Sink, handle, true); err != nil {
+			fmt.Printf("replay: persisting %s/%s: %v\n", row.SessionID, row.Sink, err)
+		}
+		replayed++
+	}
+
+	fmt.Printf("replay: resent %d session(s), skipped %d\n", replayed, skipped)
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import "time"
+
+// StoredSession is one row of persisted delivery state: the handle a given
+// sink returned for a given session, and whether that delivery was ever
+// confirmed (i.e. the Send/Edit call returned without error).
+type StoredSession struct {
+	SessionID   string
+	Sink        string
+	MessageID   string
+	Confirmed   bool
+	DeliveredAt time.Time
+}
+
+// SessionStore persists which sessions have already produced a notification
+// on which sinks, and the message handle returned for each, so a process
+// restart doesn't turn an edit into a duplicate post.
+type SessionStore interface {
+	// MarkProcessed records that sessionID produced messageID on sink,
+	// confirmed meaning the delivery round-tripped successfully.
+	MarkProcessed(sessionID, sink, messageID string, confirmed bool) error
+	// ClaimPending atomically reserves sessionID/sink for a first delivery
+	// by inserting a pending placeholder row only if none exists yet.
+	// claimed is true if this call won the race and must go on to call
+	// Send; false means a row already existed (another caller is mid-send,
+	// or it was already delivered) and this caller must not call Send too.
+	ClaimPending(sessionID, sink string) (claimed bool, err error)
+	// ReleaseClaim removes a still-pending (never successfully delivered)
+	// placeholder row left by a failed Send, so a later Notify call can
+	// retry instead of being stuck believing the session was claimed.
+	ReleaseClaim(sessionID, sink string) error
+	// LookupMessage returns the message handle previously recorded for
+	// sessionID on sink, if any.
+	LookupMessage(sessionID, sink string) (messageID string, found bool, err error)
+	// List returns every persisted session/sink row, most recent first.
+	List() ([]StoredSession, error)
+	// Prune deletes rows older than olderThan, measured from DeliveredAt.
+	Prune(olderThan time.Duration) error
+}
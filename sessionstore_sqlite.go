@@ -0,0 +1,141 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteSessionStore is the default SessionStore, backed by a single
+// on-disk SQLite database so state survives restarts without requiring an
+// external service.
+type SQLiteSessionStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteSessionStore opens (creating if necessary) the SQLite database
+// at path and ensures its schema is in place.
+func NewSQLiteSessionStore(path string) (*SQLiteSessionStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("sessionstore: opening %s: %v", path, err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS session_messages (
+	session_id   TEXT NOT NULL,
+	sink         TEXT NOT NULL,
+	message_id   TEXT NOT NULL,
+	confirmed    INTEGER NOT NULL DEFAULT 0,
+	delivered_at INTEGER NOT NULL,
+	PRIMARY KEY (session_id, sink)
+);`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sessionstore: creating schema: %v", err)
+	}
+
+	return &SQLiteSessionStore{db: db}, nil
+}
+
+func (s *SQLiteSessionStore) MarkProcessed(sessionID, sink, messageID string, confirmed bool) error {
+	_, err := s.db.Exec(
+		`INSERT INTO session_messages (session_id, sink, message_id, confirmed, delivered_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(session_id, sink) DO UPDATE SET
+		   message_id = excluded.message_id,
+		   confirmed = excluded.confirmed,
+		   delivered_at = excluded.delivered_at`,
+		sessionID, sink, messageID, confirmed, time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("sessionstore: marking %s/%s processed: %v", sessionID, sink, err)
+	}
+	return nil
+}
+
+func (s *SQLiteSessionStore) ClaimPending(sessionID, sink string) (bool, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO session_messages (session_id, sink, message_id, confirmed, delivered_at)
+		 VALUES (?, ?, '', 0, ?)
+		 ON CONFLICT(session_id, sink) DO NOTHING`,
+		sessionID, sink, time.Now().Unix(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("sessionstore: claiming %s/%s: %v", sessionID, sink, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("sessionstore: checking claim result for %s/%s: %v", sessionID, sink, err)
+	}
+	return affected == 1, nil
+}
+
+func (s *SQLiteSessionStore) ReleaseClaim(sessionID, sink string) error {
+	_, err := s.db.Exec(
+		`DELETE FROM session_messages WHERE session_id = ? AND sink = ? AND confirmed = 0`,
+		sessionID, sink,
+	)
+	if err != nil {
+		return fmt.Errorf("sessionstore: releasing claim %s/%s: %v", sessionID, sink, err)
+	}
+	return nil
+}
+
+func (s *SQLiteSessionStore) LookupMessage(sessionID, sink string) (string, bool, error) {
+	var messageID string
+	err := s.db.QueryRow(
+		`SELECT message_id FROM session_messages WHERE session_id = ? AND sink = ?`,
+		sessionID, sink,
+	).Scan(&messageID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("sessionstore: looking up %s/%s: %v", sessionID, sink, err)
+	}
+	return messageID, true, nil
+}
+
+func (s *SQLiteSessionStore) List() ([]StoredSession, error) {
+	rows, err := s.db.Query(
+		`SELECT session_id, sink, message_id, confirmed, delivered_at
+		 FROM session_messages ORDER BY delivered_at DESC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sessionstore: listing: %v", err)
+	}
+	defer rows.Close()
+
+	var sessions []StoredSession
+	for rows.Next() {
+		var (
+			row       StoredSession
+			confirmed int
+			deliverAt int64
+		)
+		if err := rows.Scan(&row.SessionID, &row.Sink, &row.MessageID, &confirmed, &deliverAt); err != nil {
+			return nil, fmt.Errorf("sessionstore: scanning row: %v", err)
+		}
+		row.Confirmed = confirmed != 0
+		row.DeliveredAt = time.Unix(deliverAt, 0)
+		sessions = append(sessions, row)
+	}
+	return sessions, rows.Err()
+}
+
+func (s *SQLiteSessionStore) Prune(olderThan time.Duration) error {
+	cutoff := time.Now().Add(-olderThan).Unix()
+	if _, err := s.db.Exec(`DELETE FROM session_messages WHERE delivered_at < ?`, cutoff); err != nil {
+		return fmt.Errorf("sessionstore: pruning: %v", err)
+	}
+	return nil
+}
+
+// Close releases the underlying database handle.
+func (s *SQLiteSessionStore) Close() error {
+	return s.db.Close()
+}
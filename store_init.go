@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	storeOnce sync.Once
+	store     SessionStore
+	storeErr  error
+)
+
+// defaultSessionStorePath is used when config does not specify one.
+const defaultSessionStorePath = "evilginx-monitor.db"
+
+// defaultPruneInterval controls how often the background pruning loop
+// checks the store against config.SessionStoreTTL.
+const defaultPruneInterval = 1 * time.Hour
+
+// getSessionStore lazily opens the configured SessionStore and starts its
+// background pruning loop. The store is opened once per process and reused
+// across calls.
+func getSessionStore(config Config) (SessionStore, error) {
+	storeOnce.Do(func() {
+		path := config.SessionStorePath
+		if path == "" {
+			path = defaultSessionStorePath
+		}
+
+		sqliteStore, err := NewSQLiteSessionStore(path)
+		if err != nil {
+			storeErr = fmt.Errorf("store: opening session store: %v", err)
+			return
+		}
+		store = sqliteStore
+
+		if config.SessionStoreTTL > 0 {
+			go prunePeriodically(store, config.SessionStoreTTL)
+		}
+	})
+	return store, storeErr
+}
+
+// prunePeriodically removes rows older than ttl from store every
+// defaultPruneInterval until the process exits.
+func prunePeriodically(store SessionStore, ttl time.Duration) {
+	ticker := time.NewTicker(defaultPruneInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := store.Prune(ttl); err != nil {
+			fmt.Println("store: pruning session store:", err)
+		}
+	}
+}